@@ -0,0 +1,57 @@
+package aws
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// callerIdentityGetter is the STS surface waitForIAMPropagation needs,
+// narrowed from *sts.STS so tests can substitute a fake that never touches
+// the network.
+type callerIdentityGetter interface {
+	GetCallerIdentity(*sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error)
+}
+
+// waitForIAMPropagation blocks, with jittered exponential backoff, until the
+// freshly minted access key is usable or timeout elapses. IAM is eventually
+// consistent, so a caller handed these credentials the instant
+// CreateAccessKey returns frequently gets InvalidClientTokenId for several
+// seconds. A zero timeout disables the wait entirely.
+func waitForIAMPropagation(accessKey, secretKey string, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	client, err := stsClientWithCredentials(accessKey, secretKey)
+	if err != nil {
+		return fmt.Errorf("error building STS client to validate new access key: %w", err)
+	}
+
+	return validateCallerIdentity(client, timeout)
+}
+
+// validateCallerIdentity retries sts:GetCallerIdentity against client until
+// it succeeds or timeout elapses.
+func validateCallerIdentity(client callerIdentityGetter, timeout time.Duration) error {
+	return retryWithBackoff(timeout, func() error {
+		_, err := client.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+		return err
+	})
+}
+
+// stsClientWithCredentials builds an STS client using a specific, static
+// access key pair rather than the backend's configured root credentials.
+func stsClientWithCredentials(accessKey, secretKey string) (*sts.STS, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials(accessKey, secretKey, ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sts.New(sess), nil
+}
@@ -0,0 +1,52 @@
+package aws
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// fakeCallerIdentityGetter implements callerIdentityGetter without touching
+// the network, returning errs[0] on the first call, errs[1] on the second,
+// and so on, then nil once errs is exhausted.
+type fakeCallerIdentityGetter struct {
+	errs  []error
+	calls int
+}
+
+func (f *fakeCallerIdentityGetter) GetCallerIdentity(*sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+	var err error
+	if f.calls < len(f.errs) {
+		err = f.errs[f.calls]
+	}
+	f.calls++
+	return &sts.GetCallerIdentityOutput{}, err
+}
+
+func TestValidateCallerIdentity_SucceedsAfterPropagationDelay(t *testing.T) {
+	fake := &fakeCallerIdentityGetter{
+		errs: []error{errors.New("InvalidClientTokenId"), errors.New("InvalidClientTokenId")},
+	}
+
+	if err := validateCallerIdentity(fake, time.Second); err != nil {
+		t.Fatalf("expected validation to eventually succeed, got %v", err)
+	}
+	if fake.calls != 3 {
+		t.Fatalf("expected 3 calls (2 failures + 1 success), got %d", fake.calls)
+	}
+}
+
+func TestValidateCallerIdentity_TimesOutIfNeverConsistent(t *testing.T) {
+	fake := &fakeCallerIdentityGetter{
+		errs: []error{
+			errors.New("1"), errors.New("2"), errors.New("3"), errors.New("4"),
+			errors.New("5"), errors.New("6"), errors.New("7"), errors.New("8"),
+		},
+	}
+
+	if err := validateCallerIdentity(fake, 50*time.Millisecond); err == nil {
+		t.Fatalf("expected an error when the credentials never become valid")
+	}
+}
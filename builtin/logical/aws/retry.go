@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	retryInitialDelay = 500 * time.Millisecond
+	retryMaxDelay     = 8 * time.Second
+)
+
+// retryWithBackoff calls fn until it succeeds or timeout elapses, sleeping
+// with exponential backoff between attempts (500ms up to a cap of 8s, each
+// jittered by up to 50% to avoid thundering-herd retries against AWS). This
+// is the same backoff shape used by the test harness's dockertest pool.Retry
+// calls, reused here so retry behavior is consistent across the codebase.
+func retryWithBackoff(timeout time.Duration, fn func() error) error {
+	deadline := time.Now().Add(timeout)
+	delay := retryInitialDelay
+
+	var err error
+	for {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+
+		time.Sleep(jitteredDelay(delay))
+		delay = nextDelay(delay)
+	}
+}
+
+// nextDelay doubles delay, capped at retryMaxDelay.
+func nextDelay(delay time.Duration) time.Duration {
+	delay *= 2
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+// jitteredDelay returns a random duration in [delay/2, delay], so retries
+// across many concurrent callers don't line up in lockstep.
+func jitteredDelay(delay time.Duration) time.Duration {
+	return delay/2 + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
@@ -0,0 +1,77 @@
+package aws
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryWithBackoff_SucceedsImmediately(t *testing.T) {
+	calls := 0
+	err := retryWithBackoff(time.Second, func() error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected exactly 1 call on immediate success, got %d", calls)
+	}
+}
+
+func TestRetryWithBackoff_SucceedsAfterRetries(t *testing.T) {
+	calls := 0
+	err := retryWithBackoff(time.Second, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error after eventual success, got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected exactly 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryWithBackoff_ReturnsLastErrorOnTimeout(t *testing.T) {
+	wantErr := errors.New("always fails")
+	calls := 0
+
+	err := retryWithBackoff(50*time.Millisecond, func() error {
+		calls++
+		return wantErr
+	})
+	if err != wantErr {
+		t.Fatalf("expected the last error to be returned, got %v", err)
+	}
+	if calls < 1 {
+		t.Fatalf("expected fn to be called at least once")
+	}
+}
+
+func TestNextDelay_DoublesAndCaps(t *testing.T) {
+	delay := retryInitialDelay
+	for i := 0; i < 10; i++ {
+		delay = nextDelay(delay)
+		if delay > retryMaxDelay {
+			t.Fatalf("delay exceeded cap: %v > %v", delay, retryMaxDelay)
+		}
+	}
+	if delay != retryMaxDelay {
+		t.Fatalf("expected delay to settle at the cap %v, got %v", retryMaxDelay, delay)
+	}
+}
+
+func TestJitteredDelay_StaysWithinHalfToFullRange(t *testing.T) {
+	delay := 2 * time.Second
+	for i := 0; i < 50; i++ {
+		jittered := jitteredDelay(delay)
+		if jittered < delay/2 || jittered > delay {
+			t.Fatalf("jittered delay %v out of range [%v, %v]", jittered, delay/2, delay)
+		}
+	}
+}
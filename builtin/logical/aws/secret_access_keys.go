@@ -38,8 +38,18 @@ func secretAccessKeys(b *backend) *framework.Secret {
 		DefaultDuration:    1 * time.Hour,
 		DefaultGracePeriod: 10 * time.Minute,
 
-		Renew:  b.secretAccessKeysRenew,
-		Revoke: secretAccessKeysRevoke,
+		// Wrapped with framework.WithRecovery so a panic here (e.g. a nil
+		// pointer while reading InternalData) can't crash the server; a
+		// panic during revoke also rolls back (deletes) the secret's IAM user.
+		Renew: framework.WithRecovery(b.Logger(), "aws", "renew", b.secretAccessKeysRenew),
+		Revoke: framework.WithRecovery(b.Logger(), "aws", "revoke", secretAccessKeysRevoke,
+			framework.WithWALRollback(func(req *logical.Request) error {
+				username, ok := req.Secret.InternalData["username"].(string)
+				if !ok {
+					return nil
+				}
+				return pathUserRollback(req, "user", map[string]interface{}{"username": username})
+			})),
 	}
 }
 
@@ -109,9 +119,51 @@ func (b *backend) secretAccessKeysAndTokenCreate(s logical.Storage,
 	}), nil
 }
 
+// secretAccessKeysAndAssumeRoleCreate returns temporary credentials obtained
+// via sts:AssumeRole against roleArn. Unlike secretAccessKeysCreate and
+// secretAccessKeysAndTokenCreate, it never creates an IAM user, so there is
+// no WAL entry to write or user to roll back: AssumeRole's credentials are
+// already temporary and expire on their own. This is the credential type
+// selected by a role's credential_type = "assumed_role".
+func (b *backend) secretAccessKeysAndAssumeRoleCreate(s logical.Storage,
+	displayName, roleArn, roleSessionName, externalID, policy string,
+	lifeTimeInSeconds *int64) (*logical.Response, error) {
+	STSClient, err := clientSTS(s)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	if roleSessionName == "" {
+		// Just like the generated IAM usernames above, we don't put the
+		// policy name in the session name because it's visible in CloudTrail.
+		roleSessionName = fmt.Sprintf("vault-%s-%d-%d", normalizeDisplayName(displayName), time.Now().Unix(), rand.Int31n(10000))
+	}
+
+	resp, err := STSClient.AssumeRole(buildAssumeRoleInput(roleArn, roleSessionName, externalID, policy, lifeTimeInSeconds))
+	if err != nil {
+		return logical.ErrorResponse(fmt.Sprintf(
+			"Error assuming role: %s", err)), nil
+	}
+
+	// Return the info!
+	return b.Secret(SecretAccessKeyType).Response(map[string]interface{}{
+		"access_key":     *resp.Credentials.AccessKeyId,
+		"secret_key":     *resp.Credentials.SecretAccessKey,
+		"security_token": *resp.Credentials.SessionToken,
+	}, map[string]interface{}{
+		"role_arn": roleArn,
+	}), nil
+}
+
+// secretAccessKeysCreate creates an IAM user and access key pair.
+// credentialValidationTimeout bounds how long to wait for the new key to
+// propagate through IAM before returning it to the caller; it is sourced
+// from a role's iam_credential_validation_timeout and may be zero to skip
+// the wait entirely.
 func (b *backend) secretAccessKeysCreate(
 	s logical.Storage,
-	displayName, policyName string, policy string) (*logical.Response, error) {
+	displayName, policyName string, policy string,
+	credentialValidationTimeout time.Duration) (*logical.Response, error) {
 	client, err := clientIAM(s)
 	if err != nil {
 		return logical.ErrorResponse(err.Error()), nil
@@ -182,6 +234,14 @@ func (b *backend) secretAccessKeysCreate(
 			"Error creating access keys: %s", err)), nil
 	}
 
+	// IAM is eventually consistent, so the access key we just created may
+	// not be usable yet. Wait for it to propagate before handing it back to
+	// the caller, unless the role has disabled this with a zero timeout.
+	if err := waitForIAMPropagation(*keyResp.AccessKey.AccessKeyId, *keyResp.AccessKey.SecretAccessKey, credentialValidationTimeout); err != nil {
+		return logical.ErrorResponse(fmt.Sprintf(
+			"Error validating new access key: %s", err)), nil
+	}
+
 	// Remove the WAL entry, we succeeded! If we fail, we don't return
 	// the secret because it'll get rolled back anyways, so we have to return
 	// an error here.
@@ -216,6 +276,12 @@ func (b *backend) secretAccessKeysRenew(
 
 func secretAccessKeysRevoke(
 	req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	// Credentials minted via sts:AssumeRole have no backing IAM user to
+	// clean up; they simply expire on their own.
+	if _, ok := req.Secret.InternalData["role_arn"]; ok {
+		return nil, nil
+	}
+
 	// Get the username from the internal data
 	usernameRaw, ok := req.Secret.InternalData["username"]
 	if !ok {
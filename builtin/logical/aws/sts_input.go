@@ -0,0 +1,24 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// buildAssumeRoleInput builds the sts:AssumeRole request for the
+// assumed_role credential type. externalID and policy (the STS session
+// policy) are optional and only set on the request when non-empty.
+func buildAssumeRoleInput(roleArn, roleSessionName, externalID, policy string, lifeTimeInSeconds *int64) *sts.AssumeRoleInput {
+	input := &sts.AssumeRoleInput{
+		RoleArn:         aws.String(roleArn),
+		RoleSessionName: aws.String(roleSessionName),
+		DurationSeconds: lifeTimeInSeconds,
+	}
+	if externalID != "" {
+		input.ExternalId = aws.String(externalID)
+	}
+	if policy != "" {
+		input.Policy = aws.String(policy)
+	}
+	return input
+}
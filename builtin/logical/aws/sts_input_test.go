@@ -0,0 +1,71 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestBuildAssumeRoleInput(t *testing.T) {
+	duration := int64(3600)
+
+	cases := []struct {
+		name            string
+		roleArn         string
+		roleSessionName string
+		externalID      string
+		policy          string
+		wantExternalID  *string
+		wantPolicy      *string
+	}{
+		{
+			name:            "minimal",
+			roleArn:         "arn:aws:iam::123456789012:role/example",
+			roleSessionName: "vault-alice-123-456",
+		},
+		{
+			name:            "with external id",
+			roleArn:         "arn:aws:iam::123456789012:role/example",
+			roleSessionName: "vault-alice-123-456",
+			externalID:      "my-external-id",
+			wantExternalID:  aws.String("my-external-id"),
+		},
+		{
+			name:            "with session policy",
+			roleArn:         "arn:aws:iam::123456789012:role/example",
+			roleSessionName: "vault-alice-123-456",
+			policy:          `{"Version":"2012-10-17"}`,
+			wantPolicy:      aws.String(`{"Version":"2012-10-17"}`),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := buildAssumeRoleInput(tc.roleArn, tc.roleSessionName, tc.externalID, tc.policy, &duration)
+
+			if got := aws.StringValue(input.RoleArn); got != tc.roleArn {
+				t.Errorf("RoleArn = %q, want %q", got, tc.roleArn)
+			}
+			if got := aws.StringValue(input.RoleSessionName); got != tc.roleSessionName {
+				t.Errorf("RoleSessionName = %q, want %q", got, tc.roleSessionName)
+			}
+			if got := aws.Int64Value(input.DurationSeconds); got != duration {
+				t.Errorf("DurationSeconds = %d, want %d", got, duration)
+			}
+
+			if tc.wantExternalID == nil && input.ExternalId != nil {
+				t.Errorf("ExternalId = %q, want unset", *input.ExternalId)
+			}
+			if tc.wantExternalID != nil && aws.StringValue(input.ExternalId) != *tc.wantExternalID {
+				t.Errorf("ExternalId = %v, want %q", input.ExternalId, *tc.wantExternalID)
+			}
+
+			if tc.wantPolicy == nil && input.Policy != nil {
+				t.Errorf("Policy = %q, want unset", *input.Policy)
+			}
+			if tc.wantPolicy != nil && aws.StringValue(input.Policy) != *tc.wantPolicy {
+				t.Errorf("Policy = %v, want %q", input.Policy, *tc.wantPolicy)
+			}
+		})
+	}
+}
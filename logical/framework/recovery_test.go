@@ -0,0 +1,126 @@
+package framework
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/vault/logical"
+)
+
+func TestWithRecovery_NoPanic(t *testing.T) {
+	rollbackCalled := false
+	handlerCalled := false
+
+	wrapped := WithRecovery(nil, "aws", "create",
+		func(req *logical.Request, data *FieldData) (*logical.Response, error) {
+			return &logical.Response{}, nil
+		},
+		WithWALRollback(func(req *logical.Request) error {
+			rollbackCalled = true
+			return nil
+		}),
+		WithPanicHandler(func(req *logical.Request, recovered interface{}, stack []byte) {
+			handlerCalled = true
+		}),
+	)
+
+	resp, err := wrapped(&logical.Request{}, &FieldData{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp == nil {
+		t.Fatalf("expected a response on the non-panicking path")
+	}
+	if rollbackCalled {
+		t.Fatalf("rollback must not run when op does not panic")
+	}
+	if handlerCalled {
+		t.Fatalf("panic handler must not run when op does not panic")
+	}
+}
+
+func TestWithRecovery_ConvertsPanicToError(t *testing.T) {
+	wrapped := WithRecovery(nil, "aws", "create",
+		func(req *logical.Request, data *FieldData) (*logical.Response, error) {
+			panic("boom")
+		},
+	)
+
+	resp, err := wrapped(&logical.Request{}, &FieldData{})
+	if err == nil {
+		t.Fatalf("expected the panic to be converted into an error")
+	}
+	if resp != nil {
+		t.Fatalf("expected a nil response after a recovered panic, got %#v", resp)
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected error to mention the panic value, got %q", err.Error())
+	}
+}
+
+func TestWithRecovery_RunsRollbackExactlyOnceOnPanic(t *testing.T) {
+	rollbackCalls := 0
+
+	wrapped := WithRecovery(nil, "aws", "revoke",
+		func(req *logical.Request, data *FieldData) (*logical.Response, error) {
+			panic("boom")
+		},
+		WithWALRollback(func(req *logical.Request) error {
+			rollbackCalls++
+			return nil
+		}),
+	)
+
+	if _, err := wrapped(&logical.Request{}, &FieldData{}); err == nil {
+		t.Fatalf("expected an error from the recovered panic")
+	}
+	if rollbackCalls != 1 {
+		t.Fatalf("expected rollback to run exactly once, ran %d times", rollbackCalls)
+	}
+}
+
+func TestWithRecovery_RollbackErrorDoesNotMaskPanic(t *testing.T) {
+	wrapped := WithRecovery(nil, "aws", "revoke",
+		func(req *logical.Request, data *FieldData) (*logical.Response, error) {
+			panic("boom")
+		},
+		WithWALRollback(func(req *logical.Request) error {
+			return errors.New("rollback also failed")
+		}),
+	)
+
+	_, err := wrapped(&logical.Request{}, &FieldData{})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected the panic error to surface even when rollback itself fails, got %v", err)
+	}
+}
+
+func TestWithRecovery_InvokesPanicHandlerWithRecoveredValue(t *testing.T) {
+	req := &logical.Request{}
+	var gotReq *logical.Request
+	var gotRecovered interface{}
+
+	wrapped := WithRecovery(nil, "aws", "create",
+		func(req *logical.Request, data *FieldData) (*logical.Response, error) {
+			panic("boom")
+		},
+		WithPanicHandler(func(r *logical.Request, recovered interface{}, stack []byte) {
+			gotReq = r
+			gotRecovered = recovered
+			if len(stack) == 0 {
+				t.Fatalf("expected a non-empty stack trace")
+			}
+		}),
+	)
+
+	if _, err := wrapped(req, &FieldData{}); err == nil {
+		t.Fatalf("expected an error from the recovered panic")
+	}
+	if gotReq != req {
+		t.Fatalf("expected panic handler to receive the original request")
+	}
+	if gotRecovered != "boom" {
+		t.Fatalf("expected panic handler to receive the recovered value, got %v", gotRecovered)
+	}
+}
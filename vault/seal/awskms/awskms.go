@@ -0,0 +1,101 @@
+// Package awskms implements a seal.Seal backed by AWS KMS, letting
+// operators auto-unseal Vault without running a Transit cluster.
+package awskms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/vault/seal"
+)
+
+func init() {
+	seal.Register("awskms", func(logger log.Logger) seal.Seal {
+		return NewSeal(logger)
+	})
+}
+
+// Seal uses a customer master key in AWS KMS as the wrapping key for
+// auto-unseal.
+type Seal struct {
+	logger log.Logger
+	client *kms.KMS
+
+	keyID string
+}
+
+// NewSeal returns an unconfigured AWS KMS Seal.
+func NewSeal(logger log.Logger) *Seal {
+	return &Seal{logger: logger}
+}
+
+// SetConfig accepts "kms_key_id" and "region", plus the usual
+// "access_key"/"secret_key"/"endpoint" overrides used to point at a
+// non-default AWS session (these fall back to the standard AWS credential
+// chain when unset).
+func (s *Seal) SetConfig(config map[string]string) (map[string]string, error) {
+	keyID := config["kms_key_id"]
+	if keyID == "" {
+		return nil, fmt.Errorf("awskms seal: kms_key_id is required")
+	}
+
+	awsConfig := &aws.Config{
+		Region:   aws.String(config["region"]),
+		Endpoint: aws.String(config["endpoint"]),
+	}
+	if config["access_key"] != "" && config["secret_key"] != "" {
+		awsConfig.Credentials = credentialsFromConfig(config)
+	}
+
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating AWS session for awskms seal: %w", err)
+	}
+
+	s.client = kms.New(sess)
+	s.keyID = keyID
+
+	return map[string]string{
+		"kms_key_id": s.keyID,
+		"region":     config["region"],
+	}, nil
+}
+
+// Encrypt calls kms:Encrypt and returns a versioned envelope wrapping the
+// resulting ciphertext blob.
+func (s *Seal) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	out, err := s.client.EncryptWithContext(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(s.keyID),
+		Plaintext: plaintext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting with awskms seal: %w", err)
+	}
+
+	return seal.EncodeEnvelope(seal.EnvelopeVersion1, out.CiphertextBlob), nil
+}
+
+// Decrypt unwraps the envelope and calls kms:Decrypt on the ciphertext blob
+// it contains.
+func (s *Seal) Decrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	version, payload, err := seal.DecodeEnvelope(blob)
+	if err != nil {
+		return nil, err
+	}
+	if version != seal.EnvelopeVersion1 {
+		return nil, fmt.Errorf("awskms seal: unsupported envelope version %d", version)
+	}
+
+	out, err := s.client.DecryptWithContext(ctx, &kms.DecryptInput{
+		CiphertextBlob: payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting with awskms seal: %w", err)
+	}
+
+	return out.Plaintext, nil
+}
@@ -0,0 +1,83 @@
+package awskms
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/helper/logging"
+	"github.com/ory/dockertest"
+)
+
+func TestAWSKMSSeal_Lifecycle(t *testing.T) {
+	cleanup, endpoint, keyID := prepareLocalstackContainer(t)
+	defer cleanup()
+
+	sealConfig := map[string]string{
+		"kms_key_id": keyID,
+		"region":     "us-east-1",
+		"endpoint":   endpoint,
+		"access_key": "test",
+		"secret_key": "test",
+	}
+	s := NewSeal(logging.NewVaultLogger(log.Trace))
+	if _, err := s.SetConfig(sealConfig); err != nil {
+		t.Fatalf("error setting seal config: %v", err)
+	}
+
+	input := []byte("foo")
+	blob, err := s.Encrypt(context.Background(), input)
+	if err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+
+	pt, err := s.Decrypt(context.Background(), blob)
+	if err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(input, pt) {
+		t.Fatalf("expected %s, got %s", input, pt)
+	}
+}
+
+// prepareLocalstackContainer spins up a localstack container offering a
+// fake KMS API and creates a customer master key in it, mirroring the
+// transit package's prepareTestContainer helper.
+func prepareLocalstackContainer(t *testing.T) (cleanup func(), endpoint, keyID string) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		t.Fatalf("Failed to connect to docker: %s", err)
+	}
+
+	dockerOptions := &dockertest.RunOptions{
+		Repository: "localstack/localstack",
+		Tag:        "latest",
+		Env:        []string{"SERVICES=kms"},
+	}
+	resource, err := pool.RunWithOptions(dockerOptions)
+	if err != nil {
+		t.Fatalf("Could not start local localstack container: %s", err)
+	}
+
+	cleanup = func() {
+		if err := pool.Purge(resource); err != nil {
+			t.Fatalf("Failed to cleanup local container: %s", err)
+		}
+	}
+
+	endpoint = fmt.Sprintf("http://127.0.0.1:%s", resource.GetPort("4566/tcp"))
+
+	if err = pool.Retry(func() error {
+		var retryErr error
+		keyID, retryErr = createLocalstackKey(endpoint)
+		return retryErr
+	}); err != nil {
+		cleanup()
+		t.Fatalf("Could not connect to localstack: %s", err)
+	}
+
+	return cleanup, endpoint, keyID
+}
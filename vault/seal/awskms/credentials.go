@@ -0,0 +1,17 @@
+package awskms
+
+import (
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// credentialsFromConfig builds a static AWS credentials provider from the
+// access_key/secret_key/session_token entries of a seal config map. Callers
+// only invoke this when access_key and secret_key are both present; the
+// standard credential chain is used otherwise.
+func credentialsFromConfig(config map[string]string) *credentials.Credentials {
+	return credentials.NewStaticCredentials(
+		config["access_key"],
+		config["secret_key"],
+		config["session_token"],
+	)
+}
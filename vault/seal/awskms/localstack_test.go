@@ -0,0 +1,27 @@
+package awskms
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// createLocalstackKey creates a customer master key against a localstack
+// KMS endpoint and returns its key ID.
+func createLocalstackKey(endpoint string) (string, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(endpoint),
+		Credentials: credentials.NewStaticCredentials("test", "test", ""),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	out, err := kms.New(sess).CreateKey(&kms.CreateKeyInput{})
+	if err != nil {
+		return "", err
+	}
+	return *out.KeyMetadata.KeyId, nil
+}
@@ -0,0 +1,111 @@
+// Package azurekeyvault implements a seal.Seal backed by Azure Key Vault.
+package azurekeyvault
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/2016-10-01/keyvault"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/vault/seal"
+)
+
+func init() {
+	seal.Register("azurekeyvault", func(logger log.Logger) seal.Seal {
+		return NewSeal(logger)
+	})
+}
+
+// Seal uses a key in Azure Key Vault as the wrapping key for auto-unseal.
+type Seal struct {
+	logger log.Logger
+	client *keyvault.BaseClient
+
+	vaultBaseURL string
+	keyName      string
+	keyVersion   string
+}
+
+// NewSeal returns an unconfigured Azure Key Vault Seal.
+func NewSeal(logger log.Logger) *Seal {
+	return &Seal{logger: logger}
+}
+
+// SetConfig accepts "vault_name" and "key_name" (and optional "key_version",
+// which defaults to the latest version), along with "tenant_id", "client_id",
+// and "client_secret" for a service principal (environment-based auth is
+// used when these are unset).
+func (s *Seal) SetConfig(config map[string]string) (map[string]string, error) {
+	if config["vault_name"] == "" || config["key_name"] == "" {
+		return nil, fmt.Errorf("azurekeyvault seal: vault_name and key_name are required")
+	}
+
+	authorizer, err := authorizerFromConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error building authorizer for azurekeyvault seal: %w", err)
+	}
+
+	client := keyvault.New()
+	client.Authorizer = authorizer
+
+	s.client = &client
+	s.vaultBaseURL = fmt.Sprintf("https://%s.vault.azure.net", config["vault_name"])
+	s.keyName = config["key_name"]
+	s.keyVersion = config["key_version"]
+
+	return map[string]string{
+		"vault_name": config["vault_name"],
+		"key_name":   s.keyName,
+	}, nil
+}
+
+func authorizerFromConfig(config map[string]string) (auth.Authorizer, error) {
+	if config["client_id"] != "" && config["client_secret"] != "" && config["tenant_id"] != "" {
+		clientCredConfig := auth.NewClientCredentialsConfig(config["client_id"], config["client_secret"], config["tenant_id"])
+		clientCredConfig.Resource = "https://vault.azure.net"
+		return clientCredConfig.Authorizer()
+	}
+	return auth.NewAuthorizerFromEnvironmentWithResource("https://vault.azure.net")
+}
+
+// Encrypt calls Key Vault WrapKey and returns a versioned envelope wrapping
+// the resulting key blob.
+func (s *Seal) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	value := base64.RawURLEncoding.EncodeToString(plaintext)
+	alg := keyvault.RSAOAEP256
+	result, err := s.client.WrapKey(ctx, s.vaultBaseURL, s.keyName, s.keyVersion, keyvault.KeyOperationsParameters{
+		Algorithm: alg,
+		Value:     &value,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting with azurekeyvault seal: %w", err)
+	}
+
+	return seal.EncodeEnvelope(seal.EnvelopeVersion1, []byte(*result.Result)), nil
+}
+
+// Decrypt unwraps the envelope and calls Key Vault UnwrapKey on the blob it
+// contains.
+func (s *Seal) Decrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	version, payload, err := seal.DecodeEnvelope(blob)
+	if err != nil {
+		return nil, err
+	}
+	if version != seal.EnvelopeVersion1 {
+		return nil, fmt.Errorf("azurekeyvault seal: unsupported envelope version %d", version)
+	}
+
+	value := string(payload)
+	alg := keyvault.RSAOAEP256
+	result, err := s.client.UnwrapKey(ctx, s.vaultBaseURL, s.keyName, s.keyVersion, keyvault.KeyOperationsParameters{
+		Algorithm: alg,
+		Value:     &value,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting with azurekeyvault seal: %w", err)
+	}
+
+	return base64.RawURLEncoding.DecodeString(*result.Result)
+}
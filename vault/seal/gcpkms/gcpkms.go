@@ -0,0 +1,102 @@
+// Package gcpkms implements a seal.Seal backed by Google Cloud KMS.
+package gcpkms
+
+import (
+	"context"
+	"fmt"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/vault/seal"
+	"google.golang.org/api/option"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+)
+
+func init() {
+	seal.Register("gcpkms", func(logger log.Logger) seal.Seal {
+		return NewSeal(logger)
+	})
+}
+
+// Seal uses a Cloud KMS crypto key as the wrapping key for auto-unseal.
+type Seal struct {
+	logger log.Logger
+	client *kms.KeyManagementClient
+
+	cryptoKeyName string
+}
+
+// NewSeal returns an unconfigured GCP KMS Seal.
+func NewSeal(logger log.Logger) *Seal {
+	return &Seal{logger: logger}
+}
+
+// SetConfig accepts "project", "region", "key_ring", and "crypto_key",
+// which together identify the Cloud KMS crypto key, plus an optional
+// "credentials" path to a service account JSON file (Application Default
+// Credentials are used when unset).
+func (s *Seal) SetConfig(config map[string]string) (map[string]string, error) {
+	for _, required := range []string{"project", "region", "key_ring", "crypto_key"} {
+		if config[required] == "" {
+			return nil, fmt.Errorf("gcpkms seal: %s is required", required)
+		}
+	}
+
+	var opts []option.ClientOption
+	if credsFile := config["credentials"]; credsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(credsFile))
+	}
+
+	client, err := kms.NewKeyManagementClient(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GCP KMS client for gcpkms seal: %w", err)
+	}
+
+	s.client = client
+	s.cryptoKeyName = fmt.Sprintf(
+		"projects/%s/locations/%s/keyRings/%s/cryptoKeys/%s",
+		config["project"], config["region"], config["key_ring"], config["crypto_key"])
+
+	return map[string]string{
+		"project":    config["project"],
+		"region":     config["region"],
+		"key_ring":   config["key_ring"],
+		"crypto_key": config["crypto_key"],
+	}, nil
+}
+
+// Encrypt calls Cloud KMS Encrypt and returns a versioned envelope wrapping
+// the resulting ciphertext.
+func (s *Seal) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	resp, err := s.client.Encrypt(ctx, &kmspb.EncryptRequest{
+		Name:      s.cryptoKeyName,
+		Plaintext: plaintext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting with gcpkms seal: %w", err)
+	}
+
+	return seal.EncodeEnvelope(seal.EnvelopeVersion1, resp.Ciphertext), nil
+}
+
+// Decrypt unwraps the envelope and calls Cloud KMS Decrypt on the
+// ciphertext it contains.
+func (s *Seal) Decrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	version, payload, err := seal.DecodeEnvelope(blob)
+	if err != nil {
+		return nil, err
+	}
+	if version != seal.EnvelopeVersion1 {
+		return nil, fmt.Errorf("gcpkms seal: unsupported envelope version %d", version)
+	}
+
+	resp, err := s.client.Decrypt(ctx, &kmspb.DecryptRequest{
+		Name:       s.cryptoKeyName,
+		Ciphertext: payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting with gcpkms seal: %w", err)
+	}
+
+	return resp.Plaintext, nil
+}
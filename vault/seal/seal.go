@@ -0,0 +1,90 @@
+// Package seal defines the common interface that Vault's auto-unseal
+// wrapping-key providers implement, plus a small registry so the server
+// config can select one by name (e.g. `seal "kms" { provider = "awskms" }`).
+package seal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	log "github.com/hashicorp/go-hclog"
+)
+
+// Providers prefix every blob they return from Encrypt with a version byte
+// so that blobs can be migrated between providers (or between envelope
+// formats of the same provider) without ambiguity about how to decode them.
+const (
+	// EnvelopeVersion1 blobs hold a provider's raw wrapped ciphertext.
+	EnvelopeVersion1 byte = 1
+
+	// EnvelopeVersion2 blobs hold a local envelope-encryption payload: the
+	// plaintext was encrypted locally with a fresh data encryption key, and
+	// only that key was sent to the provider to be wrapped. See the
+	// transit provider for the reference implementation.
+	EnvelopeVersion2 byte = 2
+)
+
+// Seal is implemented by every wrapping-key provider (Vault's own Transit
+// engine, AWS KMS, GCP KMS, Azure Key Vault, ...). SetConfig is called once
+// with the provider-specific configuration map parsed from Vault's server
+// config and returns a map of information suitable for display/audit (never
+// secrets). Encrypt and Decrypt operate on opaque, provider-defined blobs;
+// callers must not assume anything about their contents beyond the leading
+// envelope version byte.
+type Seal interface {
+	SetConfig(config map[string]string) (map[string]string, error)
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, blob []byte) ([]byte, error)
+}
+
+// Factory constructs a new, unconfigured Seal. SetConfig must be called on
+// the result before it is used.
+type Factory func(logger log.Logger) Seal
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]Factory{}
+)
+
+// Register makes a Seal provider available under name. It is meant to be
+// called from a provider package's init() function. Register panics if the
+// same name is registered twice, since that indicates a programming error.
+func Register(name string, factory Factory) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	if _, ok := providers[name]; ok {
+		panic(fmt.Sprintf("seal: provider %q already registered", name))
+	}
+	providers[name] = factory
+}
+
+// New looks up the provider registered under name and constructs a new,
+// unconfigured Seal from it.
+func New(name string, logger log.Logger) (Seal, error) {
+	providersMu.RLock()
+	factory, ok := providers[name]
+	providersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("seal: no provider registered with name %q", name)
+	}
+	return factory(logger), nil
+}
+
+// EncodeEnvelope prefixes payload with the given envelope version byte.
+func EncodeEnvelope(version byte, payload []byte) []byte {
+	blob := make([]byte, 0, len(payload)+1)
+	blob = append(blob, version)
+	blob = append(blob, payload...)
+	return blob
+}
+
+// DecodeEnvelope splits a blob produced by EncodeEnvelope back into its
+// version byte and payload.
+func DecodeEnvelope(blob []byte) (version byte, payload []byte, err error) {
+	if len(blob) < 1 {
+		return 0, nil, fmt.Errorf("seal: envelope blob is too short to contain a version byte")
+	}
+	return blob[0], blob[1:], nil
+}
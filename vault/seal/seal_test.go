@@ -0,0 +1,72 @@
+package seal
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	log "github.com/hashicorp/go-hclog"
+)
+
+type testSeal struct{}
+
+func (testSeal) SetConfig(config map[string]string) (map[string]string, error) { return nil, nil }
+func (testSeal) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)  { return plaintext, nil }
+func (testSeal) Decrypt(ctx context.Context, blob []byte) ([]byte, error)      { return blob, nil }
+
+func TestRegister_PanicsOnDuplicateName(t *testing.T) {
+	name := "test-register-duplicate"
+	Register(name, func(log.Logger) Seal { return testSeal{} })
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected Register to panic on a duplicate name")
+		}
+	}()
+	Register(name, func(log.Logger) Seal { return testSeal{} })
+}
+
+func TestNew_ErrorsForUnregisteredName(t *testing.T) {
+	if _, err := New("test-new-unregistered-provider", nil); err == nil {
+		t.Fatalf("expected an error for an unregistered provider name")
+	}
+}
+
+func TestNew_ReturnsRegisteredProvider(t *testing.T) {
+	name := "test-new-registered"
+	Register(name, func(log.Logger) Seal { return testSeal{} })
+
+	s, err := New(name, log.NewNullLogger())
+	if err != nil {
+		t.Fatalf("New: %s", err)
+	}
+	if _, ok := s.(testSeal); !ok {
+		t.Fatalf("expected New to return the registered Seal implementation")
+	}
+}
+
+func TestEncodeDecodeEnvelope_RoundTrip(t *testing.T) {
+	payload := []byte("wrapped ciphertext")
+
+	blob := EncodeEnvelope(EnvelopeVersion2, payload)
+
+	version, got, err := DecodeEnvelope(blob)
+	if err != nil {
+		t.Fatalf("DecodeEnvelope: %s", err)
+	}
+	if version != EnvelopeVersion2 {
+		t.Fatalf("expected version %d, got %d", EnvelopeVersion2, version)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("expected payload %q, got %q", payload, got)
+	}
+}
+
+func TestDecodeEnvelope_ErrorsOnEmptyBlob(t *testing.T) {
+	if _, _, err := DecodeEnvelope(nil); err == nil {
+		t.Fatalf("expected an error for a blob shorter than 1 byte")
+	}
+	if _, _, err := DecodeEnvelope([]byte{}); err == nil {
+		t.Fatalf("expected an error for a blob shorter than 1 byte")
+	}
+}
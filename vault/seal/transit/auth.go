@@ -0,0 +1,160 @@
+package transit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// authConfig holds the auth_method-specific settings parsed out of a
+// transit seal config. When method is empty the seal uses the raw "token"
+// value supplied in the config and never re-authenticates itself.
+type authConfig struct {
+	method string // "", "approle", "kubernetes"
+
+	// approle
+	roleID       string
+	secretIDFile string
+
+	// kubernetes
+	jwtPath string
+	role    string
+}
+
+func authConfigFromMap(config map[string]string) authConfig {
+	return authConfig{
+		method:       config["auth_method"],
+		roleID:       config["role_id"],
+		secretIDFile: config["secret_id_file"],
+		jwtPath:      config["jwt_path"],
+		role:         config["role"],
+	}
+}
+
+// login authenticates against the configured auth method and sets the
+// resulting client token on client. It is called once during SetConfig and
+// again by the renewal loop any time renewal fails or Encrypt/Decrypt sees a
+// 403.
+func (s *Seal) login(client *api.Client) (*api.Secret, error) {
+	switch s.auth.method {
+	case "approle":
+		return s.loginAppRole(client)
+	case "kubernetes":
+		return s.loginKubernetes(client)
+	default:
+		return nil, fmt.Errorf("transit seal: unsupported auth_method %q", s.auth.method)
+	}
+}
+
+func (s *Seal) loginAppRole(client *api.Client) (*api.Secret, error) {
+	secretID, err := ioutil.ReadFile(s.auth.secretIDFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading secret_id_file for transit seal approle auth: %w", err)
+	}
+
+	secret, err := client.Logical().Write("auth/approle/login", map[string]interface{}{
+		"role_id":   s.auth.roleID,
+		"secret_id": strings.TrimSpace(string(secretID)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error logging in with approle for transit seal: %w", err)
+	}
+	if secret == nil || secret.Auth == nil {
+		return nil, fmt.Errorf("no auth info returned from approle login")
+	}
+
+	client.SetToken(secret.Auth.ClientToken)
+	return secret, nil
+}
+
+func (s *Seal) loginKubernetes(client *api.Client) (*api.Secret, error) {
+	jwt, err := ioutil.ReadFile(s.auth.jwtPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading jwt_path for transit seal kubernetes auth: %w", err)
+	}
+
+	secret, err := client.Logical().Write("auth/kubernetes/login", map[string]interface{}{
+		"role": s.auth.role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error logging in with kubernetes auth for transit seal: %w", err)
+	}
+	if secret == nil || secret.Auth == nil {
+		return nil, fmt.Errorf("no auth info returned from kubernetes login")
+	}
+
+	client.SetToken(secret.Auth.ClientToken)
+	return secret, nil
+}
+
+// runReauthenticator keeps the seal's client token alive for the lifetime of
+// the process. It tries to renew the existing token first, and falls back
+// to a fresh login whenever renewal fails, so a long-lived root-ish token
+// never needs to be embedded directly in Vault's config.
+func (s *Seal) runReauthenticator(leaseDuration int, stopCh chan struct{}) {
+	renewIn := time.Duration(leaseDuration) * time.Second / 2
+	if renewIn <= 0 {
+		renewIn = 30 * time.Second
+	}
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(renewIn):
+		}
+
+		secret, err := s.client.Auth().Token().RenewSelf(leaseDuration)
+		if err != nil || secret == nil || secret.Auth == nil {
+			if s.logger != nil {
+				s.logger.Warn("transit seal: token renewal failed, re-authenticating", "error", err)
+			}
+
+			loginSecret, loginErr := s.login(s.client)
+			if loginErr != nil {
+				if s.logger != nil {
+					s.logger.Error("transit seal: re-authentication failed", "error", loginErr)
+				}
+				renewIn = 30 * time.Second
+				continue
+			}
+
+			// Re-login succeeded: use its lease, not the failed RenewSelf
+			// result, which may be nil or have a nil Auth.
+			secret = loginSecret
+		}
+
+		leaseDuration = secret.Auth.LeaseDuration
+		renewIn = time.Duration(leaseDuration) * time.Second / 2
+		if renewIn <= 0 {
+			renewIn = 30 * time.Second
+		}
+	}
+}
+
+// reauthenticateOn403 re-logs-in if err represents an HTTP 403 from the
+// Transit mount, which typically means the token backing this seal expired
+// or was revoked out from under it. It is a no-op for seals configured with
+// a static token, since there is nothing to log back in with.
+func (s *Seal) reauthenticateOn403(err error) bool {
+	if s.auth.method == "" || !isForbidden(err) {
+		return false
+	}
+	_, loginErr := s.login(s.client)
+	return loginErr == nil
+}
+
+func isForbidden(err error) bool {
+	if err == nil {
+		return false
+	}
+	if respErr, ok := err.(*api.ResponseError); ok {
+		return respErr.StatusCode == http.StatusForbidden
+	}
+	return false
+}
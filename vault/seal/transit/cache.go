@@ -0,0 +1,73 @@
+package transit
+
+import (
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	defaultDEKCacheSize = 500
+	defaultDEKCacheTTL  = 10 * time.Minute
+)
+
+// dekCacheEntry is the value stored in dekCache, carrying its own expiry so
+// entries can be evicted by TTL in addition to the LRU's size bound.
+type dekCacheEntry struct {
+	dek     []byte
+	expires time.Time
+}
+
+// dekCache caches unwrapped data encryption keys, keyed by their wrapped
+// (Transit-encrypted) form, so repeated decrypts of the same seal blob --
+// common during unseal and replication catch-up -- skip the round trip to
+// Transit entirely.
+type dekCache struct {
+	mu    sync.Mutex
+	cache *lru.Cache
+	ttl   time.Duration
+}
+
+func newDEKCache(size int, ttl time.Duration) (*dekCache, error) {
+	if size <= 0 {
+		size = defaultDEKCacheSize
+	}
+	if ttl <= 0 {
+		ttl = defaultDEKCacheTTL
+	}
+
+	c, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &dekCache{cache: c, ttl: ttl}, nil
+}
+
+func (c *dekCache) get(wrappedDEK []byte) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := string(wrappedDEK)
+	raw, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	entry := raw.(dekCacheEntry)
+	if time.Now().After(entry.expires) {
+		c.cache.Remove(key)
+		return nil, false
+	}
+	return entry.dek, true
+}
+
+func (c *dekCache) put(wrappedDEK, dek []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache.Add(string(wrappedDEK), dekCacheEntry{
+		dek:     dek,
+		expires: time.Now().Add(c.ttl),
+	})
+}
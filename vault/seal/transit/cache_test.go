@@ -0,0 +1,88 @@
+package transit
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestDEKCache_PutGetRoundTrip(t *testing.T) {
+	c, err := newDEKCache(10, time.Minute)
+	if err != nil {
+		t.Fatalf("newDEKCache: %s", err)
+	}
+
+	wrapped := []byte("wrapped-dek-1")
+	dek := []byte("the-real-dek")
+	c.put(wrapped, dek)
+
+	got, ok := c.get(wrapped)
+	if !ok {
+		t.Fatalf("expected a cache hit")
+	}
+	if !bytes.Equal(got, dek) {
+		t.Fatalf("expected %q, got %q", dek, got)
+	}
+}
+
+func TestDEKCache_MissOnUnknownKey(t *testing.T) {
+	c, err := newDEKCache(10, time.Minute)
+	if err != nil {
+		t.Fatalf("newDEKCache: %s", err)
+	}
+
+	if _, ok := c.get([]byte("never-cached")); ok {
+		t.Fatalf("expected a cache miss for a key that was never put")
+	}
+}
+
+func TestDEKCache_EntriesExpireAfterTTL(t *testing.T) {
+	c, err := newDEKCache(10, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("newDEKCache: %s", err)
+	}
+
+	wrapped := []byte("wrapped-dek")
+	c.put(wrapped, []byte("dek"))
+
+	if _, ok := c.get(wrapped); !ok {
+		t.Fatalf("expected a cache hit immediately after put")
+	}
+
+	time.Sleep(25 * time.Millisecond)
+
+	if _, ok := c.get(wrapped); ok {
+		t.Fatalf("expected the entry to have expired after its TTL")
+	}
+}
+
+func TestDEKCache_EvictsLeastRecentlyUsedBeyondSize(t *testing.T) {
+	c, err := newDEKCache(2, time.Minute)
+	if err != nil {
+		t.Fatalf("newDEKCache: %s", err)
+	}
+
+	c.put([]byte("a"), []byte("dek-a"))
+	c.put([]byte("b"), []byte("dek-b"))
+	c.put([]byte("c"), []byte("dek-c")) // should evict "a", the least recently used
+
+	if _, ok := c.get([]byte("a")); ok {
+		t.Fatalf("expected \"a\" to have been evicted once the cache exceeded its size")
+	}
+	if _, ok := c.get([]byte("b")); !ok {
+		t.Fatalf("expected \"b\" to still be cached")
+	}
+	if _, ok := c.get([]byte("c")); !ok {
+		t.Fatalf("expected \"c\" to still be cached")
+	}
+}
+
+func TestNewDEKCache_AppliesDefaultsForZeroValues(t *testing.T) {
+	c, err := newDEKCache(0, 0)
+	if err != nil {
+		t.Fatalf("newDEKCache: %s", err)
+	}
+	if c.ttl != defaultDEKCacheTTL {
+		t.Fatalf("expected default TTL %v, got %v", defaultDEKCacheTTL, c.ttl)
+	}
+}
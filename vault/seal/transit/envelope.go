@@ -0,0 +1,81 @@
+package transit
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+)
+
+const dekSize = 32 // AES-256
+
+// envelopeV2 is the JSON payload carried inside an EnvelopeVersion2 blob.
+// The plaintext was encrypted locally with DEK under AES-GCM; only the
+// wrapped (Transit-encrypted) form of DEK ever leaves the process.
+type envelopeV2 struct {
+	WrappedDEK []byte `json:"wrapped_dek"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// sealLocally generates a fresh DEK, AES-GCM encrypts plaintext with it, and
+// returns the resulting nonce and ciphertext. The caller is responsible for
+// wrapping dek through Transit; only the wrapped form goes into the blob.
+func sealLocally(plaintext []byte) (dek, nonce, ciphertext []byte, err error) {
+	dek = make([]byte, dekSize)
+	if _, err = rand.Read(dek); err != nil {
+		return nil, nil, nil, fmt.Errorf("error generating data encryption key: %w", err)
+	}
+
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	nonce = make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, nil, nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+
+	ciphertext = aead.Seal(nil, nonce, plaintext, nil)
+	return dek, nonce, ciphertext, nil
+}
+
+// openLocally decrypts ciphertext with dek and nonce.
+func openLocally(dek, nonce, ciphertext []byte) ([]byte, error) {
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting local envelope: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error constructing AES cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error constructing AES-GCM: %w", err)
+	}
+	return aead, nil
+}
+
+func encodeEnvelopeV2(env envelopeV2) ([]byte, error) {
+	return json.Marshal(env)
+}
+
+func decodeEnvelopeV2(payload []byte) (envelopeV2, error) {
+	var env envelopeV2
+	if err := json.Unmarshal(payload, &env); err != nil {
+		return envelopeV2{}, fmt.Errorf("error decoding local envelope: %w", err)
+	}
+	return env, nil
+}
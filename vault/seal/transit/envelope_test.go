@@ -0,0 +1,86 @@
+package transit
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSealLocally_OpenLocally_RoundTrip(t *testing.T) {
+	plaintext := []byte("super secret root key share")
+
+	dek, nonce, ciphertext, err := sealLocally(plaintext)
+	if err != nil {
+		t.Fatalf("sealLocally: %s", err)
+	}
+	if len(dek) != dekSize {
+		t.Fatalf("expected a %d-byte DEK, got %d", dekSize, len(dek))
+	}
+	if bytes.Equal(ciphertext, plaintext) {
+		t.Fatalf("ciphertext must not equal plaintext")
+	}
+
+	got, err := openLocally(dek, nonce, ciphertext)
+	if err != nil {
+		t.Fatalf("openLocally: %s", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("expected %q, got %q", plaintext, got)
+	}
+}
+
+func TestSealLocally_NoncesAreNotReused(t *testing.T) {
+	plaintext := []byte("foo")
+
+	_, nonce1, _, err := sealLocally(plaintext)
+	if err != nil {
+		t.Fatalf("sealLocally: %s", err)
+	}
+	_, nonce2, _, err := sealLocally(plaintext)
+	if err != nil {
+		t.Fatalf("sealLocally: %s", err)
+	}
+
+	if bytes.Equal(nonce1, nonce2) {
+		t.Fatalf("expected distinct nonces across calls, got the same nonce twice")
+	}
+}
+
+func TestOpenLocally_WrongDEKFails(t *testing.T) {
+	plaintext := []byte("foo")
+
+	_, nonce, ciphertext, err := sealLocally(plaintext)
+	if err != nil {
+		t.Fatalf("sealLocally: %s", err)
+	}
+
+	wrongDEK, _, _, err := sealLocally(plaintext)
+	if err != nil {
+		t.Fatalf("sealLocally: %s", err)
+	}
+
+	if _, err := openLocally(wrongDEK, nonce, ciphertext); err == nil {
+		t.Fatalf("expected decryption under the wrong DEK to fail")
+	}
+}
+
+func TestEnvelopeV2_EncodeDecodeRoundTrip(t *testing.T) {
+	want := envelopeV2{
+		WrappedDEK: []byte("wrapped-dek"),
+		Nonce:      []byte("nonce"),
+		Ciphertext: []byte("ciphertext"),
+	}
+
+	payload, err := encodeEnvelopeV2(want)
+	if err != nil {
+		t.Fatalf("encodeEnvelopeV2: %s", err)
+	}
+
+	got, err := decodeEnvelopeV2(payload)
+	if err != nil {
+		t.Fatalf("decodeEnvelopeV2: %s", err)
+	}
+
+	if !bytes.Equal(got.WrappedDEK, want.WrappedDEK) || !bytes.Equal(got.Nonce, want.Nonce) || !bytes.Equal(got.Ciphertext, want.Ciphertext) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,249 @@
+// Package transit implements a seal.Seal that wraps Vault's own Transit
+// secrets engine, delegating Encrypt/Decrypt to a remote (or same-cluster)
+// Transit mount. It is the original auto-unseal provider and the reference
+// implementation that the other provider packages (awskms, gcpkms,
+// azurekeyvault) follow.
+package transit
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/api"
+	"github.com/hashicorp/vault/vault/seal"
+)
+
+func init() {
+	seal.Register("transit", func(logger log.Logger) seal.Seal {
+		return NewSeal(logger)
+	})
+}
+
+// Seal uses a Transit mount on a (possibly remote) Vault cluster as the
+// wrapping key for auto-unseal.
+type Seal struct {
+	logger log.Logger
+	client *api.Client
+
+	mountPath string
+	keyName   string
+
+	auth     authConfig
+	stopCh   chan struct{}
+	dekCache *dekCache
+}
+
+// NewSeal returns an unconfigured transit Seal. SetConfig must be called
+// before Encrypt/Decrypt can be used.
+func NewSeal(logger log.Logger) *Seal {
+	return &Seal{logger: logger}
+}
+
+// SetConfig accepts at minimum "address", "mount_path", and "key_name",
+// along with optional "tls_ca_cert", "tls_client_cert", and "tls_client_key"
+// for mutual TLS to the Transit cluster. The client token can either be
+// supplied directly via "token", or obtained (and transparently renewed or
+// re-obtained) via "auth_method" set to "approle" (with "role_id" and
+// "secret_id_file") or "kubernetes" (with "jwt_path" and "role").
+//
+// Encrypt/Decrypt use local envelope encryption with a data encryption key
+// wrapped by Transit; "dek_cache_size" (default 500) and "dek_cache_ttl"
+// (a Go duration string, default "10m") bound the LRU cache of unwrapped
+// DEKs keyed by their wrapped form.
+func (s *Seal) SetConfig(config map[string]string) (map[string]string, error) {
+	clientConfig := api.DefaultConfig()
+	clientConfig.Address = config["address"]
+
+	tlsConfig := &api.TLSConfig{
+		CACert:     config["tls_ca_cert"],
+		ClientCert: config["tls_client_cert"],
+		ClientKey:  config["tls_client_key"],
+	}
+	if err := clientConfig.ConfigureTLS(tlsConfig); err != nil {
+		return nil, fmt.Errorf("error configuring transit seal TLS: %w", err)
+	}
+
+	client, err := api.NewClient(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating transit seal client: %w", err)
+	}
+
+	cacheSize, _ := strconv.Atoi(config["dek_cache_size"])
+	cacheTTL, _ := time.ParseDuration(config["dek_cache_ttl"])
+	dekCache, err := newDEKCache(cacheSize, cacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("error creating transit seal DEK cache: %w", err)
+	}
+
+	// A prior SetConfig call (e.g. a config reload) may have left a
+	// re-authenticator goroutine running against the old client; stop it
+	// before swapping in the new one so it doesn't leak.
+	s.Close()
+
+	s.auth = authConfigFromMap(config)
+	s.client = client
+	s.mountPath = config["mount_path"]
+	s.keyName = config["key_name"]
+	s.dekCache = dekCache
+
+	var loginSecret *api.Secret
+	if s.auth.method == "" {
+		client.SetToken(config["token"])
+	} else {
+		loginSecret, err = s.login(client)
+		if err != nil {
+			return nil, err
+		}
+		s.stopCh = make(chan struct{})
+		go s.runReauthenticator(loginSecret.Auth.LeaseDuration, s.stopCh)
+	}
+
+	return map[string]string{
+		"address":    config["address"],
+		"mount_path": s.mountPath,
+		"key_name":   s.keyName,
+	}, nil
+}
+
+// Close stops the background re-authenticator goroutine started by
+// SetConfig, if one is running. It is safe to call on a Seal that was never
+// configured with an auth_method, and safe to call more than once. Callers
+// that tear down a transit Seal (or replace it with a fresh SetConfig call)
+// must call Close first so the old goroutine doesn't leak.
+func (s *Seal) Close() error {
+	if s.stopCh != nil {
+		close(s.stopCh)
+		s.stopCh = nil
+	}
+	return nil
+}
+
+// Encrypt implements envelope encryption: plaintext is AES-GCM encrypted
+// locally under a freshly generated DEK, and only that DEK is sent to
+// Transit to be wrapped. This avoids a remote round trip for the (often
+// much larger) plaintext itself, at the cost of one small remote call per
+// Encrypt to wrap the DEK.
+func (s *Seal) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	dek, nonce, ciphertext, err := sealLocally(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedDEK, err := s.transitEncrypt(ctx, dek)
+	if err != nil {
+		return nil, fmt.Errorf("error wrapping data encryption key with transit seal: %w", err)
+	}
+	s.dekCache.put(wrappedDEK, dek)
+
+	payload, err := encodeEnvelopeV2(envelopeV2{
+		WrappedDEK: wrappedDEK,
+		Nonce:      nonce,
+		Ciphertext: ciphertext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return seal.EncodeEnvelope(seal.EnvelopeVersion2, payload), nil
+}
+
+// Decrypt unwraps the envelope. EnvelopeVersion2 blobs (the current format)
+// are decrypted locally once their DEK is unwrapped, which the DEK cache
+// may serve without a remote call at all. EnvelopeVersion1 blobs, produced
+// before envelope encryption was introduced, still decode by asking
+// Transit to decrypt the ciphertext directly.
+func (s *Seal) Decrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	version, payload, err := seal.DecodeEnvelope(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	switch version {
+	case seal.EnvelopeVersion1:
+		plaintext, err := s.transitDecrypt(ctx, payload)
+		if err != nil {
+			return nil, fmt.Errorf("error decrypting with transit seal: %w", err)
+		}
+		return plaintext, nil
+
+	case seal.EnvelopeVersion2:
+		env, err := decodeEnvelopeV2(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		dek, cached := s.dekCache.get(env.WrappedDEK)
+		if !cached {
+			dek, err = s.transitDecrypt(ctx, env.WrappedDEK)
+			if err != nil {
+				return nil, fmt.Errorf("error unwrapping data encryption key with transit seal: %w", err)
+			}
+			s.dekCache.put(env.WrappedDEK, dek)
+		}
+
+		return openLocally(dek, env.Nonce, env.Ciphertext)
+
+	default:
+		return nil, fmt.Errorf("transit seal: unsupported envelope version %d", version)
+	}
+}
+
+// transitEncrypt base64-encodes raw and sends it to the configured Transit
+// mount's encrypt endpoint, returning the resulting ciphertext string as
+// bytes.
+func (s *Seal) transitEncrypt(_ context.Context, raw []byte) ([]byte, error) {
+	data := map[string]interface{}{
+		"plaintext": base64.StdEncoding.EncodeToString(raw),
+	}
+
+	resp, err := s.client.Logical().Write(path.Join(s.mountPath, "encrypt", s.keyName), data)
+	if err != nil && s.reauthenticateOn403(err) {
+		resp, err = s.client.Logical().Write(path.Join(s.mountPath, "encrypt", s.keyName), data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Data == nil {
+		return nil, fmt.Errorf("no data returned from transit encrypt")
+	}
+	ciphertext, ok := resp.Data["ciphertext"].(string)
+	if !ok {
+		return nil, fmt.Errorf("no ciphertext returned from transit encrypt")
+	}
+
+	return []byte(ciphertext), nil
+}
+
+// transitDecrypt sends a Transit ciphertext string to the configured
+// Transit mount's decrypt endpoint and returns the decoded plaintext.
+func (s *Seal) transitDecrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	data := map[string]interface{}{
+		"ciphertext": string(ciphertext),
+	}
+
+	resp, err := s.client.Logical().Write(path.Join(s.mountPath, "decrypt", s.keyName), data)
+	if err != nil && s.reauthenticateOn403(err) {
+		resp, err = s.client.Logical().Write(path.Join(s.mountPath, "decrypt", s.keyName), data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Data == nil {
+		return nil, fmt.Errorf("no data returned from transit decrypt")
+	}
+	plaintext, ok := resp.Data["plaintext"].(string)
+	if !ok {
+		return nil, fmt.Errorf("no plaintext returned from transit decrypt")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding transit plaintext: %w", err)
+	}
+	return decoded, nil
+}